@@ -1,72 +1,386 @@
 package cache
 
 import (
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// Cache holds values with type string and
-// allows to retrieve them using keys of time strings.
+// EvictionReason describes why a key/value pair was removed from the cache.
+type EvictionReason int
+
+const (
+	// Expired means the entry's deadline had passed when it was removed.
+	Expired EvictionReason = iota
+	// Deleted means the entry was removed explicitly, e.g. via Delete.
+	Deleted
+	// Replaced means the entry's value was overwritten before eviction.
+	Replaced
+)
+
+// Cache holds key/value pairs and allows retrieving them by key.
 // Key/value pairs can expire if given a deadline using PutTill method.
-type Cache struct {
-	RWMutex  sync.RWMutex
-	cacheMap map[string]cacheItem
+//
+// Cache is a thin handle onto a shared *cache[K, V]: copying a Cache
+// (as Namespace does) gives a distinct view over the same underlying
+// Store, hooks, and janitor, all reached through the same *cache[K, V]
+// pointer. That shared *cache[K, V] is itself referenced by the
+// janitor goroutine's own stack for as long as the goroutine runs, so
+// it can never become unreachable while its janitor is alive — a
+// runtime.SetFinalizer on it directly would simply never fire. Instead,
+// each handle that must keep a janitor alive (the one NewCacheWithJanitor
+// returns, and every Cache obtained from its Namespace) carries its own
+// janitorToken, a small heap object the janitor never references. The
+// janitor is stopped once every outstanding token has been finalized,
+// i.e. once every handle over it has been garbage collected.
+type Cache[K comparable, V any] struct {
+	*cache[K, V]
+	namespace string
+	janitor   *janitorToken[K, V]
+}
+
+// StringCache is the string-to-string cache this package started out
+// as, kept as a type alias for callers that don't need generic keys or
+// values.
+type StringCache = Cache[string, string]
+
+// KeyAndValue is a snapshot of an evicted entry, delivered in bulk by
+// OnEvictedBulk.
+type KeyAndValue[K comparable, V any] struct {
+	Key      K
+	Value    V
+	Deadline time.Time
+}
+
+// cache holds the state shared by every Cache handle over it, and, when
+// present, its janitor.
+//
+// RWMutex serializes every read-modify-write against store (Get's lazy
+// expiry, Put, PutTill, Delete, Add, Replace, Update, and the janitor's
+// deleteExpired), not just the hook fields it used to guard alone.
+// Store implementations may still lock internally, but it's this outer
+// lock that makes check-then-act sequences like Add/Replace/Update
+// atomic across the whole Cache, including across Namespace views,
+// which all share the same *cache.
+type cache[K comparable, V any] struct {
+	RWMutex       sync.RWMutex
+	store         Store[K, V]
+	keyFunc       KeyFunc[K]
+	keyMeta       map[K]keyMeta[K]
+	onEvicted     func(key K, value V, reason EvictionReason)
+	onEvictedBulk func(items []KeyAndValue[K, V])
+	stopJanitor   chan struct{}
+	stopOnce      sync.Once
+	janitorRefs   int32
+}
+
+// janitorToken is a small heap-allocated object, one per Cache handle
+// that shares a background janitor. Unlike the shared *cache[K, V]
+// itself, a token is never referenced by the janitor goroutine, so it
+// becomes unreachable — and its finalizer runs — as soon as the last
+// Cache handle holding it is gone. The finalizer decrements c's
+// outstanding-token count and stops the janitor once it reaches zero.
+type janitorToken[K comparable, V any] struct {
+	c *cache[K, V]
+}
+
+// newJanitorToken registers a new handle sharing c's janitor and
+// returns the token that keeps it counted. Callers must arrange for the
+// returned token to be reachable for exactly as long as the handle it
+// was created for.
+func newJanitorToken[K comparable, V any](c *cache[K, V]) *janitorToken[K, V] {
+	atomic.AddInt32(&c.janitorRefs, 1)
+	t := &janitorToken[K, V]{c: c}
+	runtime.SetFinalizer(t, func(t *janitorToken[K, V]) {
+		if atomic.AddInt32(&t.c.janitorRefs, -1) == 0 {
+			t.c.stopJanitorLoop()
+		}
+	})
+	return t
+}
+
+// keyMeta records, for a KeyFunc-transformed store key, the original
+// key and namespace it was produced from, so Keys and deleteExpired can
+// translate store keys back for callers.
+type keyMeta[K comparable] struct {
+	origKey   K
+	namespace string
+}
+
+// rememberKeyMeta records that storeKey was produced from key under
+// namespace via KeyFunc, so Keys and deleteExpired can report the
+// original key, scoped to the right namespace, instead of the
+// store-internal one. Callers must hold RWMutex.
+func (c *cache[K, V]) rememberKeyMeta(storeKey, key K, namespace string) {
+	if c.keyMeta == nil {
+		c.keyMeta = make(map[K]keyMeta[K])
+	}
+	c.keyMeta[storeKey] = keyMeta[K]{origKey: key, namespace: namespace}
+}
+
+// origKeyFor returns the original, pre-KeyFunc key for storeKey, or
+// storeKey itself if no KeyFunc is configured or none was recorded.
+// Callers must hold RWMutex.
+func (c *cache[K, V]) origKeyFor(storeKey K) K {
+	if meta, ok := c.keyMeta[storeKey]; ok {
+		return meta.origKey
+	}
+	return storeKey
 }
 
-// cacheItem holds the value and deadline of a key/value pair
-type cacheItem struct {
-	value    string
-	deadline time.Time
-	expired  bool
+// namespaceFor returns the namespace storeKey was stored under, or the
+// zero-value namespace if no KeyFunc is configured or none was
+// recorded. Callers must hold RWMutex.
+func (c *cache[K, V]) namespaceFor(storeKey K) string {
+	return c.keyMeta[storeKey].namespace
 }
 
-// NewCache returns a new cache instance
-func NewCache() Cache {
-	cachemap := make(map[string]cacheItem)
-	return Cache{
-		cacheMap: cachemap,
+// forgetKeyMeta drops any recorded metadata for storeKey once its entry
+// is gone. Callers must hold RWMutex.
+func (c *cache[K, V]) forgetKeyMeta(storeKey K) {
+	if c.keyMeta != nil {
+		delete(c.keyMeta, storeKey)
+	}
+}
+
+// stopJanitorLoop closes stopJanitor exactly once, signalling runJanitor
+// to return. It is safe to call with no janitor running.
+func (c *cache[K, V]) stopJanitorLoop() {
+	if c.stopJanitor == nil {
+		return
+	}
+	c.stopOnce.Do(func() {
+		close(c.stopJanitor)
+	})
+}
+
+// NewCache returns a new cache instance backed by an in-memory Store.
+func NewCache[K comparable, V any]() Cache[K, V] {
+	return Cache[K, V]{
+		cache: &cache[K, V]{store: newMapStore[K, V]()},
+	}
+}
+
+// NewStringCache returns a new string-to-string cache instance, for
+// callers that don't need this package's generic key/value support.
+func NewStringCache() StringCache {
+	return NewCache[string, string]()
+}
+
+// NewCacheWithJanitor returns a new cache instance, backed by an
+// in-memory Store, with a background janitor goroutine that wakes up
+// every cleanupInterval and deletes entries whose deadline has passed,
+// so expired entries that are never re-read don't accumulate in
+// memory. Call StopJanitor (or Close) to stop the goroutine once the
+// cache is no longer needed; it is also stopped automatically once
+// every Cache handle over it — including any obtained via Namespace —
+// has been garbage collected.
+func NewCacheWithJanitor[K comparable, V any](cleanupInterval time.Duration) *Cache[K, V] {
+	c := &cache[K, V]{
+		store:       newMapStore[K, V](),
+		stopJanitor: make(chan struct{}),
+	}
+
+	go c.runJanitor(cleanupInterval)
+	// The token, not c itself, carries the finalizer: the janitor
+	// goroutine started above holds its own reference to c for as long
+	// as it runs, so c can never become unreachable while its janitor is
+	// alive and a finalizer on c directly would never fire. The token has
+	// no such reference, so it becomes unreachable as soon as this
+	// wrapper (and any Namespace view sharing it) is.
+	return &Cache[K, V]{cache: c, janitor: newJanitorToken(c)}
+}
+
+// Namespace returns a view of the cache scoped to ns: keys passed to
+// Get, Put, PutTill, Delete, Add, Replace, and Update on the returned
+// Cache are transformed by the configured KeyFunc before reaching the
+// Store, so callers can isolate keyspaces (e.g. per-tenant) inside one
+// Cache without standing up separate Cache instances or Store
+// backends. The returned Cache shares its Store, hooks, and janitor
+// with c. If no KeyFunc was configured, ns has no effect.
+func (c Cache[K, V]) Namespace(ns string) Cache[K, V] {
+	view := Cache[K, V]{cache: c.cache, namespace: ns}
+	if c.cache.stopJanitor != nil {
+		view.janitor = newJanitorToken(c.cache)
+	}
+	return view
+}
+
+// storeKey composes c.namespace and key via the configured KeyFunc into
+// the key actually used to address the Store. It is the identity
+// function when no KeyFunc was configured.
+func (c *Cache[K, V]) storeKey(key K) K {
+	if c.keyFunc == nil {
+		return key
+	}
+	return c.keyFunc(c.namespace, key)
+}
+
+// OnEvicted registers a callback that fires when a key/value pair is
+// removed from the cache by lazy expiry in Get, by Delete, or by a
+// janitor sweep; for janitor sweeps it is called only when no
+// OnEvictedBulk callback is set. It is not called for plain overwrites
+// via Put or PutTill.
+func (c *Cache[K, V]) OnEvicted(f func(key K, value V, reason EvictionReason)) {
+	c.RWMutex.Lock()
+	defer c.RWMutex.Unlock()
+
+	c.onEvicted = f
+}
+
+// OnEvictedBulk registers a callback that receives all entries removed
+// by a single janitor sweep in one call, once the lock has been
+// released, so downstream systems can amortize per-eviction overhead.
+// It takes precedence over OnEvicted for janitor sweeps; OnEvicted is
+// used instead only when no bulk callback is set. It has no effect on
+// lazy expiry in Get, which always reports through OnEvicted.
+func (c *Cache[K, V]) OnEvictedBulk(f func(items []KeyAndValue[K, V])) {
+	c.RWMutex.Lock()
+	defer c.RWMutex.Unlock()
+
+	c.onEvictedBulk = f
+}
+
+// StopJanitor stops the background janitor goroutine started by
+// NewCacheWithJanitor. It is a no-op if the cache has no janitor or if
+// it has already been stopped.
+func (c *Cache[K, V]) StopJanitor() {
+	c.stopJanitorLoop()
+}
+
+// Close stops the background janitor goroutine, if any. It is
+// equivalent to StopJanitor and is provided to match the common Close
+// idiom used by comparable cache packages.
+func (c *Cache[K, V]) Close() {
+	c.StopJanitor()
+}
+
+// runJanitor periodically deletes expired entries until stopJanitor is closed.
+func (c *cache[K, V]) runJanitor(cleanupInterval time.Duration) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.deleteExpired()
+		case <-c.stopJanitor:
+			return
+		}
+	}
+}
+
+// deleteExpired removes all expired entries and reports them once
+// collected, preferring a single OnEvictedBulk call over one OnEvicted
+// call per entry. The whole scan runs under RWMutex so it can't observe
+// or clobber an in-flight Put/PutTill/Add/Replace/Update/Delete/Get.
+func (c *cache[K, V]) deleteExpired() {
+	now := time.Now()
+
+	c.RWMutex.Lock()
+	var evicted []KeyAndValue[K, V]
+	for _, storeKey := range c.store.Keys() {
+		value, deadline, ok := c.store.Get(storeKey)
+		if !ok || deadline.IsZero() || deadline.After(now) {
+			continue
+		}
+		if _, deleted := c.store.Delete(storeKey); deleted {
+			evicted = append(evicted, KeyAndValue[K, V]{Key: c.origKeyFor(storeKey), Value: value, Deadline: deadline})
+			c.forgetKeyMeta(storeKey)
+		}
+	}
+	onEvicted := c.onEvicted
+	onEvictedBulk := c.onEvictedBulk
+	c.RWMutex.Unlock()
+
+	if len(evicted) == 0 {
+		return
+	}
+	if onEvictedBulk != nil {
+		onEvictedBulk(evicted)
+		return
+	}
+	if onEvicted != nil {
+		for _, e := range evicted {
+			onEvicted(e.Key, e.Value, Expired)
+		}
 	}
 }
 
 // Get returns the value associated with the key and the boolean ok (true if exists, false if not),
 // if the deadline of the key/value pair has not been exceeded yet.
-func (c *Cache) Get(key string) (string, bool) {
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	storeKey := c.storeKey(key)
+
 	c.RWMutex.RLock()
-	defer c.RWMutex.RUnlock()
+	value, deadline, ok := c.store.Get(storeKey)
+	c.RWMutex.RUnlock()
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if deadline.IsZero() || deadline.After(time.Now()) {
+		return value, true
+	}
 
-	if item, ok := c.cacheMap[key]; ok {
-		item.expired = item.deadline.Before(time.Now())
-		if !item.expired || item.deadline.IsZero() {
-			return item.value, true
+	// The snapshot above is stale; re-check under the write lock before
+	// deleting so a concurrent Put/PutTill that refreshed this key in
+	// between doesn't get deleted out from under it.
+	c.RWMutex.Lock()
+	curValue, curDeadline, curOK := c.store.Get(storeKey)
+	var deleted bool
+	if curOK && curDeadline.Equal(deadline) {
+		_, deleted = c.store.Delete(storeKey)
+		if deleted {
+			c.forgetKeyMeta(storeKey)
 		}
 	}
-	return "", false
+	onEvicted := c.onEvicted
+	c.RWMutex.Unlock()
+
+	if deleted && onEvicted != nil {
+		onEvicted(key, curValue, Expired)
+	}
+	var zero V
+	return zero, false
 }
 
 // Put places a value with an associated key into cache.
 // Value put with this method never expired (have infinite deadline).
 // Putting into the existing key overwrites the value
-func (c *Cache) Put(key, value string) {
+func (c *Cache[K, V]) Put(key K, value V) {
+	storeKey := c.storeKey(key)
+
 	c.RWMutex.Lock()
 	defer c.RWMutex.Unlock()
 
-	c.cacheMap[key] = cacheItem{
-		value:    value,
-		deadline: time.Time{},
-		expired:  false,
+	if storeKey != key {
+		c.rememberKeyMeta(storeKey, key, c.namespace)
 	}
+	c.store.Put(storeKey, value)
 }
 
-// Keys returns a slice of keys in the cache (not expired)
-func (c *Cache) Keys() []string {
+// Keys returns a slice of keys in the cache (not expired). When a
+// KeyFunc is configured, only keys belonging to c's own namespace are
+// returned, translated back to the form they were put in with — other
+// namespaces sharing the same Store are not visible here, the same way
+// they aren't through Get.
+func (c *Cache[K, V]) Keys() []K {
+	now := time.Now()
+
 	c.RWMutex.RLock()
 	defer c.RWMutex.RUnlock()
 
-	keys := make([]string, 0, len(c.cacheMap))
-	for key, item := range c.cacheMap {
-		item.expired = item.deadline.Before(time.Now())
-		if !item.expired || item.deadline.IsZero() {
-			keys = append(keys, key)
+	raw := c.store.Keys()
+	keys := make([]K, 0, len(raw))
+	for _, storeKey := range raw {
+		if c.keyFunc != nil && c.namespaceFor(storeKey) != c.namespace {
+			continue
+		}
+		_, deadline, ok := c.store.Get(storeKey)
+		if ok && (deadline.IsZero() || deadline.After(now)) {
+			keys = append(keys, c.origKeyFor(storeKey))
 		}
 	}
 	return keys
@@ -75,13 +389,14 @@ func (c *Cache) Keys() []string {
 // PutTill places a value with an associated key into cache.
 // Value put with this method expires after the given deadline.
 // Putting into the existing key overwrites the value
-func (c *Cache) PutTill(key, value string, deadline time.Time) {
+func (c *Cache[K, V]) PutTill(key K, value V, deadline time.Time) {
+	storeKey := c.storeKey(key)
+
 	c.RWMutex.Lock()
 	defer c.RWMutex.Unlock()
 
-	c.cacheMap[key] = cacheItem{
-		value:    value,
-		deadline: deadline,
-		expired:  false,
+	if storeKey != key {
+		c.rememberKeyMeta(storeKey, key, c.namespace)
 	}
+	c.store.PutTill(storeKey, value, deadline)
 }
@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPutGetAndExpiry(t *testing.T) {
+	c := NewStringCache()
+
+	c.Put("a", "1")
+	if v, ok := c.Get("a"); !ok || v != "1" {
+		t.Fatalf("Get(a) = %q, %v; want 1, true", v, ok)
+	}
+
+	c.PutTill("b", "2", time.Now().Add(-time.Second))
+	if v, ok := c.Get("b"); ok {
+		t.Fatalf("Get(b) = %q, %v; want zero value, false for an already-expired key", v, ok)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	c := NewStringCache()
+
+	c.Put("a", "1")
+	c.PutTill("b", "2", time.Now().Add(-time.Second))
+	c.Put("c", "3")
+
+	got := map[string]bool{}
+	for _, k := range c.Keys() {
+		got[k] = true
+	}
+	if want := (map[string]bool{"a": true, "c": true}); len(got) != len(want) || !got["a"] || !got["c"] {
+		t.Fatalf("Keys() = %v, want %v", got, want)
+	}
+}
+
+func TestNamespaceIsolatesKeys(t *testing.T) {
+	base := NewCacheWithOptions[string, string](
+		WithKeyFunc[string, string](func(namespace, key string) string {
+			return namespace + ":" + key
+		}),
+	)
+
+	a := base.Namespace("tenantA")
+	b := base.Namespace("tenantB")
+
+	a.Put("foo", "1")
+	b.Put("bar", "2")
+
+	if got := a.Keys(); len(got) != 1 || got[0] != "foo" {
+		t.Fatalf("a.Keys() = %v, want [foo]", got)
+	}
+	if got := b.Keys(); len(got) != 1 || got[0] != "bar" {
+		t.Fatalf("b.Keys() = %v, want [bar]", got)
+	}
+
+	if v, ok := a.Get("bar"); ok {
+		t.Fatalf("a.Get(bar) = %q, %v; tenantA should not see tenantB's key", v, ok)
+	}
+}
+
+func TestOnEvictedFromLazyExpiry(t *testing.T) {
+	c := NewStringCache()
+
+	var mu sync.Mutex
+	var gotReason EvictionReason
+	var gotKey string
+	c.OnEvicted(func(key string, value string, reason EvictionReason) {
+		mu.Lock()
+		defer mu.Unlock()
+		gotKey, gotReason = key, reason
+	})
+
+	c.PutTill("a", "1", time.Now().Add(-time.Second))
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("Get(a) should report the expired key as absent")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if gotKey != "a" || gotReason != Expired {
+		t.Fatalf("OnEvicted called with (%q, %v); want (a, Expired)", gotKey, gotReason)
+	}
+}
+
+func TestJanitorSweepAndOnEvictedBulk(t *testing.T) {
+	c := NewCacheWithJanitor[string, string](10 * time.Millisecond)
+	defer c.StopJanitor()
+
+	done := make(chan []KeyAndValue[string, string], 1)
+	c.OnEvictedBulk(func(items []KeyAndValue[string, string]) {
+		done <- items
+	})
+
+	c.PutTill("a", "1", time.Now().Add(5*time.Millisecond))
+
+	select {
+	case items := <-done:
+		if len(items) != 1 || items[0].Key != "a" {
+			t.Fatalf("OnEvictedBulk got %v, want one item for key a", items)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("janitor did not sweep the expired key in time")
+	}
+}
+
+func TestJanitorStopsAfterOnlyHandleIsGCed(t *testing.T) {
+	c := NewCacheWithJanitor[string, string](10 * time.Millisecond)
+	stopJanitor := c.cache.stopJanitor
+
+	c = nil
+	runtime.GC()
+	runtime.GC()
+
+	select {
+	case <-stopJanitor:
+	case <-time.After(time.Second):
+		t.Fatal("janitor did not stop after the only *Cache handle holding it was GCed")
+	}
+}
+
+func TestNamespaceKeepsJanitorAliveAfterOriginalIsGCed(t *testing.T) {
+	original := NewCacheWithJanitor[string, string](10 * time.Millisecond)
+	ns := original.Namespace("t")
+
+	done := make(chan struct{}, 1)
+	ns.OnEvicted(func(key string, value string, reason EvictionReason) {
+		select {
+		case done <- struct{}{}:
+		default:
+		}
+	})
+
+	original = nil
+	runtime.GC()
+	runtime.GC()
+
+	ns.PutTill("a", "1", time.Now().Add(5*time.Millisecond))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("janitor stopped sweeping after the original *Cache handle was GCed while a Namespace view was still alive")
+	}
+	ns.StopJanitor()
+}
+
+func TestConcurrentGetDoesNotLoseRacingPutTill(t *testing.T) {
+	c := NewStringCache()
+	c.PutTill("a", "old", time.Now().Add(10*time.Millisecond))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		time.Sleep(10 * time.Millisecond)
+		c.PutTill("a", "new", time.Now().Add(time.Hour))
+	}()
+	go func() {
+		defer wg.Done()
+		time.Sleep(10 * time.Millisecond)
+		c.Get("a")
+	}()
+	wg.Wait()
+
+	if v, ok := c.Get("a"); !ok || v != "new" {
+		t.Fatalf("Get(a) = %q, %v; want new, true (a racing Get must not delete a freshly-PutTill value)", v, ok)
+	}
+}
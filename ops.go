@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrKeyExists is returned by Add when the key is already present and unexpired.
+var ErrKeyExists = errors.New("cache: key already exists")
+
+// ErrKeyNotFound is returned by Replace and Update when the key is missing or expired.
+var ErrKeyNotFound = errors.New("cache: key not found")
+
+// Delete removes the key/value pair for key, returning its value and
+// whether it existed. An already-expired entry is not considered to
+// exist: it is purged the same as a live one, but reported to OnEvicted
+// with reason Expired instead of Deleted, and Delete returns ok=false
+// for it, consistent with Get, Add, Replace, and Update.
+func (c *Cache[K, V]) Delete(key K) (V, bool) {
+	storeKey := c.storeKey(key)
+
+	c.RWMutex.Lock()
+	value, deadline, existed := c.store.Get(storeKey)
+	live := existed && (deadline.IsZero() || deadline.After(time.Now()))
+	var deleted bool
+	if existed {
+		value, deleted = c.store.Delete(storeKey)
+		if deleted {
+			c.forgetKeyMeta(storeKey)
+		}
+	}
+	onEvicted := c.onEvicted
+	c.RWMutex.Unlock()
+
+	if deleted && onEvicted != nil {
+		if live {
+			onEvicted(key, value, Deleted)
+		} else {
+			onEvicted(key, value, Expired)
+		}
+	}
+	return value, live
+}
+
+// Add places a value with an associated key into the cache, but only if
+// the key is not already present and unexpired. It returns ErrKeyExists
+// otherwise. The existence check and the write happen under a single
+// lock so concurrent Adds on the same key can't both succeed.
+func (c *Cache[K, V]) Add(key K, value V, deadline time.Time) error {
+	storeKey := c.storeKey(key)
+
+	c.RWMutex.Lock()
+	defer c.RWMutex.Unlock()
+
+	if _, existingDeadline, ok := c.store.Get(storeKey); ok && (existingDeadline.IsZero() || existingDeadline.After(time.Now())) {
+		return ErrKeyExists
+	}
+	if storeKey != key {
+		c.rememberKeyMeta(storeKey, key, c.namespace)
+	}
+	c.store.PutTill(storeKey, value, deadline)
+	return nil
+}
+
+// Replace overwrites the value and deadline of an existing, unexpired
+// key. It returns ErrKeyNotFound if the key is missing or has already
+// expired. The check and the write happen under a single lock so a
+// concurrent expiry or Delete can't slip in between them.
+func (c *Cache[K, V]) Replace(key K, value V, deadline time.Time) error {
+	storeKey := c.storeKey(key)
+
+	c.RWMutex.Lock()
+	defer c.RWMutex.Unlock()
+
+	_, existingDeadline, ok := c.store.Get(storeKey)
+	if !ok || (!existingDeadline.IsZero() && existingDeadline.Before(time.Now())) {
+		return ErrKeyNotFound
+	}
+	if storeKey != key {
+		c.rememberKeyMeta(storeKey, key, c.namespace)
+	}
+	c.store.PutTill(storeKey, value, deadline)
+	return nil
+}
+
+// Update rewrites the value of an existing, unexpired key while
+// preserving its original deadline. It returns ErrKeyNotFound if the
+// key is missing or has already expired. The check and the write
+// happen under a single lock so a concurrent expiry or Delete can't
+// slip in between them.
+func (c *Cache[K, V]) Update(key K, value V) error {
+	storeKey := c.storeKey(key)
+
+	c.RWMutex.Lock()
+	defer c.RWMutex.Unlock()
+
+	_, deadline, ok := c.store.Get(storeKey)
+	if !ok || (!deadline.IsZero() && deadline.Before(time.Now())) {
+		return ErrKeyNotFound
+	}
+	if storeKey != key {
+		c.rememberKeyMeta(storeKey, key, c.namespace)
+	}
+	c.store.PutTill(storeKey, value, deadline)
+	return nil
+}
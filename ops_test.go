@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAddReplaceUpdateDelete(t *testing.T) {
+	c := NewStringCache()
+
+	if err := c.Add("a", "1", time.Time{}); err != nil {
+		t.Fatalf("Add(a) on a missing key: %v", err)
+	}
+	if err := c.Add("a", "2", time.Time{}); !errors.Is(err, ErrKeyExists) {
+		t.Fatalf("Add(a) on an existing key = %v, want ErrKeyExists", err)
+	}
+
+	if err := c.Replace("a", "2", time.Time{}); err != nil {
+		t.Fatalf("Replace(a) on an existing key: %v", err)
+	}
+	if v, _ := c.Get("a"); v != "2" {
+		t.Fatalf("Get(a) = %q, want 2 after Replace", v)
+	}
+	if err := c.Replace("missing", "x", time.Time{}); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("Replace(missing) = %v, want ErrKeyNotFound", err)
+	}
+
+	if err := c.Update("a", "3"); err != nil {
+		t.Fatalf("Update(a) on an existing key: %v", err)
+	}
+	if v, _ := c.Get("a"); v != "3" {
+		t.Fatalf("Get(a) = %q, want 3 after Update", v)
+	}
+	if err := c.Update("missing", "x"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("Update(missing) = %v, want ErrKeyNotFound", err)
+	}
+
+	if v, ok := c.Delete("a"); !ok || v != "3" {
+		t.Fatalf("Delete(a) = %q, %v; want 3, true", v, ok)
+	}
+	if _, ok := c.Delete("a"); ok {
+		t.Fatal("Delete(a) on an already-deleted key should report false")
+	}
+}
+
+func TestUpdatePreservesDeadline(t *testing.T) {
+	c := NewStringCache()
+	deadline := time.Now().Add(20 * time.Millisecond)
+	c.PutTill("a", "1", deadline)
+
+	if err := c.Update("a", "2"); err != nil {
+		t.Fatalf("Update(a): %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("Get(a) should report the key as expired; Update must preserve the original deadline, not reset it")
+	}
+}
+
+func TestDeleteReportsExpiredKeyAsNotExisting(t *testing.T) {
+	c := NewStringCache()
+	var reasons []EvictionReason
+	c.OnEvicted(func(key, value string, reason EvictionReason) {
+		reasons = append(reasons, reason)
+	})
+	c.PutTill("a", "1", time.Now().Add(-time.Second))
+
+	if v, ok := c.Delete("a"); ok {
+		t.Fatalf("Delete(a) = %q, %v; want ok=false for an already-expired entry", v, ok)
+	}
+	if len(reasons) != 1 || reasons[0] != Expired {
+		t.Fatalf("OnEvicted reasons = %v, want [Expired]; Delete must report an expired entry as Expired, not Deleted", reasons)
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("Get(a) should still report false; Delete must purge the expired entry it found")
+	}
+}
+
+func TestConcurrentAddOnlyOneSucceeds(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		c := NewStringCache()
+
+		const goroutines = 8
+		var wg sync.WaitGroup
+		successes := make([]bool, goroutines)
+		wg.Add(goroutines)
+		for g := 0; g < goroutines; g++ {
+			g := g
+			go func() {
+				defer wg.Done()
+				successes[g] = c.Add("a", "1", time.Time{}) == nil
+			}()
+		}
+		wg.Wait()
+
+		count := 0
+		for _, ok := range successes {
+			if ok {
+				count++
+			}
+		}
+		if count != 1 {
+			t.Fatalf("iteration %d: %d of %d concurrent Add(a) calls succeeded, want exactly 1", i, count, goroutines)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"encoding/gob"
+	"io"
+	"os"
+	"time"
+)
+
+// gobItem is the exported counterpart of a Store entry used for gob
+// encoding, since gob only serializes exported fields.
+type gobItem[V any] struct {
+	Value    V
+	Deadline time.Time
+}
+
+// Save writes the cache's live entries to w using encoding/gob. Entries
+// whose deadline has already passed are skipped. When a KeyFunc is
+// configured, only entries belonging to c's own namespace are written,
+// under the key they were put in with — other namespaces sharing the
+// same Store are not visible here, the same way they aren't through
+// Keys.
+func (c *Cache[K, V]) Save(w io.Writer) error {
+	c.RWMutex.Lock()
+	defer c.RWMutex.Unlock()
+
+	now := time.Now()
+	keys := c.store.Keys()
+	snapshot := make(map[K]gobItem[V], len(keys))
+	for _, storeKey := range keys {
+		if c.keyFunc != nil && c.namespaceFor(storeKey) != c.namespace {
+			continue
+		}
+		value, deadline, ok := c.store.Get(storeKey)
+		if !ok || (!deadline.IsZero() && deadline.Before(now)) {
+			continue
+		}
+		snapshot[c.origKeyFor(storeKey)] = gobItem[V]{Value: value, Deadline: deadline}
+	}
+	return gob.NewEncoder(w).Encode(snapshot)
+}
+
+// SaveFile writes the cache's live entries to the file at path, creating
+// or truncating it as needed.
+func (c *Cache[K, V]) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return c.Save(f)
+}
+
+// Load reads entries previously written by Save from r and merges them
+// into the cache: entries that had already expired when saved are
+// skipped, and keys that are already live (present and unexpired) in
+// the cache are left alone so Load never clobbers fresher in-memory
+// data. A key that exists but has already expired is not considered
+// live, so a fresher on-disk value for it is loaded rather than
+// skipped. When a KeyFunc is configured, entries are restored into c's
+// own namespace, the same way Put does.
+func (c *Cache[K, V]) Load(r io.Reader) error {
+	var snapshot map[K]gobItem[V]
+	if err := gob.NewDecoder(r).Decode(&snapshot); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	c.RWMutex.Lock()
+	defer c.RWMutex.Unlock()
+
+	for key, item := range snapshot {
+		if !item.Deadline.IsZero() && item.Deadline.Before(now) {
+			continue
+		}
+		storeKey := c.storeKey(key)
+		if _, existingDeadline, exists := c.store.Get(storeKey); exists && (existingDeadline.IsZero() || existingDeadline.After(now)) {
+			continue
+		}
+		if storeKey != key {
+			c.rememberKeyMeta(storeKey, key, c.namespace)
+		}
+		c.store.PutTill(storeKey, item.Value, item.Deadline)
+	}
+	return nil
+}
+
+// LoadFile reads entries previously written by SaveFile from the file at
+// path and merges them into the cache, as Load does.
+func (c *Cache[K, V]) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return c.Load(f)
+}
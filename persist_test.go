@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	src := NewStringCache()
+	src.Put("a", "1")
+	src.PutTill("b", "2", time.Now().Add(time.Hour))
+	src.PutTill("expired", "3", time.Now().Add(-time.Second))
+
+	var buf bytes.Buffer
+	if err := src.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	dst := NewStringCache()
+	if err := dst.Load(&buf); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if v, ok := dst.Get("a"); !ok || v != "1" {
+		t.Fatalf("Get(a) = %q, %v; want 1, true", v, ok)
+	}
+	if v, ok := dst.Get("b"); !ok || v != "2" {
+		t.Fatalf("Get(b) = %q, %v; want 2, true", v, ok)
+	}
+	if _, ok := dst.Get("expired"); ok {
+		t.Fatal("Get(expired) should report false; Save should not have written an already-expired entry")
+	}
+}
+
+func TestLoadDoesNotClobberLiveKeys(t *testing.T) {
+	src := NewStringCache()
+	src.Put("a", "from-file")
+	var buf bytes.Buffer
+	if err := src.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	dst := NewStringCache()
+	dst.Put("a", "live")
+	if err := dst.Load(&buf); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if v, ok := dst.Get("a"); !ok || v != "live" {
+		t.Fatalf("Get(a) = %q, %v; want live, true — Load must not overwrite a live in-memory key", v, ok)
+	}
+}
+
+func TestSaveLoadRoundTripThroughNamespaces(t *testing.T) {
+	base := NewCacheWithOptions[string, string](
+		WithKeyFunc[string, string](func(namespace, key string) string {
+			return namespace + ":" + key
+		}),
+	)
+	tenant1 := base.Namespace("tenant1")
+	tenant2 := base.Namespace("tenant2")
+
+	tenant1.Put("a", "1")
+	tenant2.Put("a", "2")
+
+	var buf bytes.Buffer
+	if err := tenant1.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if saved := buf.String(); strings.Contains(saved, "tenant2") {
+		t.Fatalf("Save wrote %q; tenant1.Save must not leak tenant2's keys", saved)
+	}
+
+	dst := NewCacheWithOptions[string, string](
+		WithKeyFunc[string, string](func(namespace, key string) string {
+			return namespace + ":" + key
+		}),
+	)
+	dstTenant1 := dst.Namespace("tenant1")
+	if err := dstTenant1.Load(&buf); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if v, ok := dstTenant1.Get("a"); !ok || v != "1" {
+		t.Fatalf("dstTenant1.Get(a) = %q, %v; want 1, true", v, ok)
+	}
+	if got := dstTenant1.Keys(); len(got) != 1 || got[0] != "a" {
+		t.Fatalf("dstTenant1.Keys() = %v, want [a]; Load must record key metadata so Keys sees the restored entry", got)
+	}
+}
+
+func TestLoadReplacesExpiredKeyWithFresherFileValue(t *testing.T) {
+	src := NewStringCache()
+	src.Put("a", "from-file")
+	var buf bytes.Buffer
+	if err := src.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	dst := NewStringCache()
+	dst.PutTill("a", "stale", time.Now().Add(-time.Second))
+	if err := dst.Load(&buf); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if v, ok := dst.Get("a"); !ok || v != "from-file" {
+		t.Fatalf("Get(a) = %q, %v; want from-file, true — an expired key is not live and should be replaced by a fresher file value", v, ok)
+	}
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Store is the pluggable backing storage for a Cache. The default,
+// used unless WithStore is given, is an in-memory mapStore. Swapping in
+// a different Store (e.g. Redis-backed or LRU-bounded) requires no
+// changes on the Cache consumer side.
+type Store[K comparable, V any] interface {
+	// Get returns the value and deadline stored for key, and whether it
+	// was present. A zero deadline means the entry never expires;
+	// Store implementations do not interpret deadlines themselves.
+	Get(key K) (value V, deadline time.Time, ok bool)
+	// Put stores value for key with no deadline, overwriting any
+	// existing entry.
+	Put(key K, value V)
+	// PutTill stores value for key with the given deadline, overwriting
+	// any existing entry.
+	PutTill(key K, value V, deadline time.Time)
+	// Delete removes the entry for key, returning its value and
+	// whether it was present.
+	Delete(key K) (value V, ok bool)
+	// Keys returns all keys currently stored, live or expired.
+	Keys() []K
+	// Len returns the number of keys currently stored, live or expired.
+	Len() int
+}
+
+// KeyFunc composes a namespace and a key into the key actually used to
+// address a Cache's Store, letting callers isolate keyspaces (e.g.
+// per-tenant) inside one Cache. See Cache.Namespace.
+type KeyFunc[K comparable] func(namespace string, key K) K
+
+// Option configures a Cache built with NewCacheWithOptions.
+type Option[K comparable, V any] func(*cache[K, V])
+
+// WithStore sets the backing Store for a Cache. If not given,
+// NewCacheWithOptions uses an in-memory Store, same as NewCache.
+func WithStore[K comparable, V any](store Store[K, V]) Option[K, V] {
+	return func(c *cache[K, V]) {
+		c.store = store
+	}
+}
+
+// WithKeyFunc sets the KeyFunc a Cache uses, in Namespace, to compose a
+// namespace and a key into the key used to address the Store.
+func WithKeyFunc[K comparable, V any](keyFunc KeyFunc[K]) Option[K, V] {
+	return func(c *cache[K, V]) {
+		c.keyFunc = keyFunc
+	}
+}
+
+// NewCacheWithOptions returns a new Cache configured by opts. Without
+// WithStore, it defaults to the same in-memory Store as NewCache.
+func NewCacheWithOptions[K comparable, V any](opts ...Option[K, V]) Cache[K, V] {
+	c := &cache[K, V]{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.store == nil {
+		c.store = newMapStore[K, V]()
+	}
+	return Cache[K, V]{cache: c}
+}
+
+// mapStore is the default in-memory Store implementation.
+type mapStore[K comparable, V any] struct {
+	mu    sync.RWMutex
+	items map[K]storeItem[V]
+}
+
+// storeItem holds the value and deadline of a mapStore entry.
+type storeItem[V any] struct {
+	value    V
+	deadline time.Time
+}
+
+func newMapStore[K comparable, V any]() *mapStore[K, V] {
+	return &mapStore[K, V]{items: make(map[K]storeItem[V])}
+}
+
+func (s *mapStore[K, V]) Get(key K) (V, time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item, ok := s.items[key]
+	return item.value, item.deadline, ok
+}
+
+func (s *mapStore[K, V]) Put(key K, value V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[key] = storeItem[V]{value: value}
+}
+
+func (s *mapStore[K, V]) PutTill(key K, value V, deadline time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[key] = storeItem[V]{value: value, deadline: deadline}
+}
+
+func (s *mapStore[K, V]) Delete(key K) (V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[key]
+	if ok {
+		delete(s.items, key)
+	}
+	return item.value, ok
+}
+
+func (s *mapStore[K, V]) Keys() []K {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]K, 0, len(s.items))
+	for key := range s.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (s *mapStore[K, V]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.items)
+}
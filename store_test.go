@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordingStore wraps a mapStore and counts calls to Get, so tests can
+// confirm a Cache built with WithStore actually routes reads through
+// the configured Store rather than some internal default.
+type recordingStore[K comparable, V any] struct {
+	*mapStore[K, V]
+	gets int32
+}
+
+func newRecordingStore[K comparable, V any]() *recordingStore[K, V] {
+	return &recordingStore[K, V]{mapStore: newMapStore[K, V]()}
+}
+
+func (s *recordingStore[K, V]) Get(key K) (V, time.Time, bool) {
+	atomic.AddInt32(&s.gets, 1)
+	return s.mapStore.Get(key)
+}
+
+func TestWithStoreIsUsed(t *testing.T) {
+	store := newRecordingStore[string, string]()
+	c := NewCacheWithOptions[string, string](WithStore[string, string](store))
+
+	c.Put("a", "1")
+	if v, ok := c.Get("a"); !ok || v != "1" {
+		t.Fatalf("Get(a) = %q, %v; want 1, true", v, ok)
+	}
+
+	if atomic.LoadInt32(&store.gets) == 0 {
+		t.Fatal("Get did not go through the Store configured via WithStore")
+	}
+}
+
+func TestNewCacheWithOptionsDefaultsToInMemoryStore(t *testing.T) {
+	c := NewCacheWithOptions[string, string]()
+
+	c.Put("a", "1")
+	if v, ok := c.Get("a"); !ok || v != "1" {
+		t.Fatalf("Get(a) = %q, %v; want 1, true", v, ok)
+	}
+}